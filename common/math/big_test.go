@@ -139,6 +139,51 @@ func BenchmarkPaddedBigBytes(b *testing.B) {
 	}
 }
 
+func TestByte(t *testing.T) {
+	tests := []struct {
+		num       *big.Int
+		padlength int
+		n         int
+		result    byte
+	}{
+		{num: big.NewInt(0), padlength: 32, n: 0, result: 0},
+		{num: big.NewInt(1), padlength: 32, n: 31, result: 1},
+		{num: big.NewInt(1), padlength: 32, n: 0, result: 0},
+		{num: big.NewInt(0xff), padlength: 32, n: 31, result: 0xff},
+		{num: big.NewInt(0xff00), padlength: 32, n: 30, result: 0xff},
+		{num: big.NewInt(1), padlength: 4, n: 4, result: 0},
+	}
+	for _, test := range tests {
+		if result := Byte(test.num, test.padlength, test.n); result != test.result {
+			t.Errorf("Byte(%d, %d, %d) = %d, want %d", test.num, test.padlength, test.n, result, test.result)
+		}
+	}
+}
+
+func TestByteMatchesPaddedBigBytes(t *testing.T) {
+	bigint := MustParseBig256("123456789123456789123456789123456789")
+	padded := PaddedBigBytes(bigint, 32)
+	for n := 0; n < 32; n++ {
+		if got, want := Byte(bigint, 32, n), padded[n]; got != want {
+			t.Errorf("Byte(x, 32, %d) = %d, want %d", n, got, want)
+		}
+	}
+}
+
+func BenchmarkByte(b *testing.B) {
+	bigint := MustParseBig256("123456789123456789123456789123456789")
+	for i := 0; i < b.N; i++ {
+		Byte(bigint, 32, 17)
+	}
+}
+
+func BenchmarkPaddedBigBytesIndex(b *testing.B) {
+	bigint := MustParseBig256("123456789123456789123456789123456789")
+	for i := 0; i < b.N; i++ {
+		_ = PaddedBigBytes(bigint, 32)[17]
+	}
+}
+
 func TestReadBits(t *testing.T) {
 	check := func(input string) {
 		want, _ := hex.DecodeString(input)
@@ -216,4 +261,4 @@ func TestExp(t *testing.T) {
 			t.Errorf("Exp(%d, %d) = %d, want %d", test.base, test.exponent, result, test.result)
 		}
 	}
-}
\ No newline at end of file
+}
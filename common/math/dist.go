@@ -0,0 +1,80 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package math
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// BigDist returns the absolute difference |a - b| of two big integers.
+func BigDist(a, b *big.Int) *big.Int {
+	d := new(big.Int).Sub(a, b)
+	return d.Abs(d)
+}
+
+// Log2 returns the floor of the base-2 logarithm of v. It returns -1 for v<=0.
+func Log2(v *big.Int) int {
+	if v.Sign() <= 0 {
+		return -1
+	}
+	return v.BitLen() - 1
+}
+
+// Log2Ceil returns the ceiling of the base-2 logarithm of v. It returns -1
+// for v<=0.
+func Log2Ceil(v *big.Int) int {
+	l2 := Log2(v)
+	if l2 < 0 {
+		return l2
+	}
+	// v is an exact power of two when only its top bit is set.
+	if new(big.Int).Lsh(big.NewInt(1), uint(l2)).Cmp(v) == 0 {
+		return l2
+	}
+	return l2 + 1
+}
+
+// NextPowerOfTwo returns the smallest power of two that is >= v. It returns 1
+// for v<=1.
+func NextPowerOfTwo(v *big.Int) *big.Int {
+	if v.Sign() <= 0 {
+		return big.NewInt(1)
+	}
+	if v.Cmp(big.NewInt(1)) == 0 {
+		return big.NewInt(1)
+	}
+	return new(big.Int).Lsh(big.NewInt(1), uint(Log2Ceil(v)))
+}
+
+// U64Log2 returns the floor of the base-2 logarithm of v. It returns -1 for
+// v==0.
+func U64Log2(v uint64) int {
+	if v == 0 {
+		return -1
+	}
+	return bits.Len64(v) - 1
+}
+
+// U64NextPowerOfTwo returns the smallest power of two that is >= v. It
+// returns 1 for v<=1.
+func U64NextPowerOfTwo(v uint64) uint64 {
+	if v <= 1 {
+		return 1
+	}
+	return 1 << uint(bits.Len64(v-1))
+}
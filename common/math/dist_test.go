@@ -0,0 +1,126 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package math
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestBigDist(t *testing.T) {
+	tests := []struct{ a, b, want *big.Int }{
+		{big.NewInt(5), big.NewInt(3), big.NewInt(2)},
+		{big.NewInt(3), big.NewInt(5), big.NewInt(2)},
+		{big.NewInt(0), big.NewInt(0), big.NewInt(0)},
+	}
+	for _, test := range tests {
+		if got := BigDist(test.a, test.b); got.Cmp(test.want) != 0 {
+			t.Errorf("BigDist(%d, %d) = %d, want %d", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestLog2(t *testing.T) {
+	tests := []struct {
+		v    *big.Int
+		want int
+	}{
+		{big.NewInt(0), -1},
+		{big.NewInt(-1), -1},
+		{big.NewInt(1), 0},
+		{big.NewInt(2), 1},
+		{big.NewInt(3), 1},
+		{big.NewInt(4), 2},
+		{BigPow(2, 255), 255},
+		{new(big.Int).Sub(BigPow(2, 256), big.NewInt(1)), 255},
+	}
+	for _, test := range tests {
+		if got := Log2(test.v); got != test.want {
+			t.Errorf("Log2(%d) = %d, want %d", test.v, got, test.want)
+		}
+	}
+}
+
+func TestLog2Ceil(t *testing.T) {
+	tests := []struct {
+		v    *big.Int
+		want int
+	}{
+		{big.NewInt(0), -1},
+		{big.NewInt(1), 0},
+		{big.NewInt(2), 1},
+		{big.NewInt(3), 2},
+		{big.NewInt(4), 2},
+		{big.NewInt(5), 3},
+		{BigPow(2, 255), 255},
+	}
+	for _, test := range tests {
+		if got := Log2Ceil(test.v); got != test.want {
+			t.Errorf("Log2Ceil(%d) = %d, want %d", test.v, got, test.want)
+		}
+	}
+}
+
+func TestNextPowerOfTwo(t *testing.T) {
+	tests := []struct{ v, want *big.Int }{
+		{big.NewInt(0), big.NewInt(1)},
+		{big.NewInt(1), big.NewInt(1)},
+		{big.NewInt(2), big.NewInt(2)},
+		{big.NewInt(3), big.NewInt(4)},
+		{big.NewInt(4), big.NewInt(4)},
+		{big.NewInt(5), big.NewInt(8)},
+	}
+	for _, test := range tests {
+		if got := NextPowerOfTwo(test.v); got.Cmp(test.want) != 0 {
+			t.Errorf("NextPowerOfTwo(%d) = %d, want %d", test.v, got, test.want)
+		}
+	}
+}
+
+func TestU64Log2(t *testing.T) {
+	tests := []struct {
+		v    uint64
+		want int
+	}{
+		{0, -1},
+		{1, 0},
+		{2, 1},
+		{3, 1},
+		{1 << 63, 63},
+	}
+	for _, test := range tests {
+		if got := U64Log2(test.v); got != test.want {
+			t.Errorf("U64Log2(%d) = %d, want %d", test.v, got, test.want)
+		}
+	}
+}
+
+func TestU64NextPowerOfTwo(t *testing.T) {
+	tests := []struct{ v, want uint64 }{
+		{0, 1},
+		{1, 1},
+		{2, 2},
+		{3, 4},
+		{4, 4},
+		{5, 8},
+	}
+	for _, test := range tests {
+		if got := U64NextPowerOfTwo(test.v); got != test.want {
+			t.Errorf("U64NextPowerOfTwo(%d) = %d, want %d", test.v, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package math
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// HexOrDecimal256 marshals a *big.Int as hex while allowing either syntax on
+// unmarshaling, so that JSON documents such as genesis/config files can use
+// whichever notation is most convenient.
+type HexOrDecimal256 big.Int
+
+// NewHexOrDecimal256 creates a new HexOrDecimal256.
+func NewHexOrDecimal256(x int64) *HexOrDecimal256 {
+	b := big.NewInt(x)
+	h := HexOrDecimal256(*b)
+	return &h
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (i *HexOrDecimal256) UnmarshalText(input []byte) error {
+	bigint, ok := ParseBig256(string(input))
+	if !ok {
+		return fmt.Errorf("invalid hex or decimal integer %q", input)
+	}
+	*i = HexOrDecimal256(*bigint)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (i *HexOrDecimal256) MarshalText() ([]byte, error) {
+	if i == nil {
+		return []byte("0x0"), nil
+	}
+	return []byte(fmt.Sprintf("%#x", (*big.Int)(i))), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting the same hex or
+// decimal syntax as UnmarshalText.
+func (i *HexOrDecimal256) UnmarshalJSON(input []byte) error {
+	var text string
+	if err := json.Unmarshal(input, &text); err != nil {
+		return err
+	}
+	return i.UnmarshalText([]byte(text))
+}
+
+// MarshalJSON implements json.Marshaler.
+func (i *HexOrDecimal256) MarshalJSON() ([]byte, error) {
+	text, err := i.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// HexOrDecimal64 marshals uint64 as hex while allowing either syntax on
+// unmarshaling, so that JSON documents such as genesis/config files can use
+// whichever notation is most convenient.
+type HexOrDecimal64 uint64
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (i *HexOrDecimal64) UnmarshalText(input []byte) error {
+	v, ok := ParseUint64(string(input))
+	if !ok {
+		return fmt.Errorf("invalid hex or decimal integer %q", input)
+	}
+	*i = HexOrDecimal64(v)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (i HexOrDecimal64) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%#x", uint64(i))), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting the same hex or
+// decimal syntax as UnmarshalText.
+func (i *HexOrDecimal64) UnmarshalJSON(input []byte) error {
+	var text string
+	if err := json.Unmarshal(input, &text); err != nil {
+		return err
+	}
+	return i.UnmarshalText([]byte(text))
+}
+
+// MarshalJSON implements json.Marshaler.
+func (i HexOrDecimal64) MarshalJSON() ([]byte, error) {
+	text, err := i.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+var (
+	_ json.Marshaler   = (*HexOrDecimal256)(nil)
+	_ json.Unmarshaler = (*HexOrDecimal256)(nil)
+	_ json.Marshaler   = HexOrDecimal64(0)
+	_ json.Unmarshaler = (*HexOrDecimal64)(nil)
+)
@@ -0,0 +1,122 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package math
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestHexOrDecimal256(t *testing.T) {
+	tests := []struct {
+		input string
+		num   *big.Int
+		ok    bool
+	}{
+		{"", big.NewInt(0), true},
+		{"0", big.NewInt(0), true},
+		{"0x0", big.NewInt(0), true},
+		{"12345678", big.NewInt(12345678), true},
+		{"0x12345678", big.NewInt(0x12345678), true},
+		{"abcdef", nil, false},
+	}
+	for _, test := range tests {
+		var num HexOrDecimal256
+		err := (&num).UnmarshalText([]byte(test.input))
+		if (err == nil) != test.ok {
+			t.Errorf("UnmarshalText(%q) -> err = %v, want ok = %t", test.input, err, test.ok)
+			continue
+		}
+		if test.num != nil && (*big.Int)(&num).Cmp(test.num) != 0 {
+			t.Errorf("UnmarshalText(%q) -> %d, want %d", test.input, (*big.Int)(&num), test.num)
+		}
+	}
+}
+
+func TestHexOrDecimal256MarshalNil(t *testing.T) {
+	var num *HexOrDecimal256
+	text, err := num.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText() on nil *HexOrDecimal256 returned error: %v", err)
+	}
+	if string(text) != "0x0" {
+		t.Errorf("MarshalText() on nil *HexOrDecimal256 = %q, want %q", text, "0x0")
+	}
+}
+
+func TestHexOrDecimal256JSONRoundTrip(t *testing.T) {
+	type config struct {
+		Value *HexOrDecimal256 `json:"value"`
+	}
+	in := config{Value: NewHexOrDecimal256(0x1234)}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal() failed: %v", err)
+	}
+	var out config
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal() failed: %v", err)
+	}
+	if (*big.Int)(out.Value).Cmp((*big.Int)(in.Value)) != 0 {
+		t.Errorf("round-trip mismatch: got %d, want %d", (*big.Int)(out.Value), (*big.Int)(in.Value))
+	}
+}
+
+func TestHexOrDecimal256MarshalJSON(t *testing.T) {
+	num := NewHexOrDecimal256(0x1234)
+	data, err := num.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON() failed: %v", err)
+	}
+	if string(data) != `"0x1234"` {
+		t.Errorf("MarshalJSON() = %s, want %s", data, `"0x1234"`)
+	}
+	var out HexOrDecimal256
+	if err := out.UnmarshalJSON(data); err != nil {
+		t.Fatalf("UnmarshalJSON() failed: %v", err)
+	}
+	if (*big.Int)(&out).Cmp((*big.Int)(num)) != 0 {
+		t.Errorf("UnmarshalJSON() round-trip mismatch: got %d, want %d", (*big.Int)(&out), (*big.Int)(num))
+	}
+}
+
+func TestHexOrDecimal64(t *testing.T) {
+	tests := []struct {
+		input string
+		num   uint64
+		ok    bool
+	}{
+		{"", 0, true},
+		{"0", 0, true},
+		{"0x0", 0, true},
+		{"12345678", 12345678, true},
+		{"0x12345678", 0x12345678, true},
+		{"abcdef", 0, false},
+	}
+	for _, test := range tests {
+		var num HexOrDecimal64
+		err := (&num).UnmarshalText([]byte(test.input))
+		if (err == nil) != test.ok {
+			t.Errorf("UnmarshalText(%q) -> err = %v, want ok = %t", test.input, err, test.ok)
+			continue
+		}
+		if test.ok && uint64(num) != test.num {
+			t.Errorf("UnmarshalText(%q) -> %d, want %d", test.input, uint64(num), test.num)
+		}
+	}
+}
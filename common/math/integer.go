@@ -0,0 +1,193 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package math
+
+import (
+	"flag"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"strconv"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+// SafeAdd returns a + b, and a bool indicating whether the addition
+// overflowed a uint64. On overflow, the returned value is the wrapped result.
+func SafeAdd(a, b uint64) (uint64, bool) {
+	sum, carry := bits.Add64(a, b, 0)
+	return sum, carry != 0
+}
+
+// SafeSub returns a - b, and a bool indicating whether the subtraction
+// underflowed a uint64. On underflow, the returned value is the wrapped
+// result.
+func SafeSub(a, b uint64) (uint64, bool) {
+	diff, borrow := bits.Sub64(a, b, 0)
+	return diff, borrow != 0
+}
+
+// SafeMul returns a * b, and a bool indicating whether the multiplication
+// overflowed a uint64. On overflow, the returned value is the wrapped result.
+func SafeMul(a, b uint64) (uint64, bool) {
+	hi, lo := bits.Mul64(a, b)
+	return lo, hi != 0
+}
+
+// ParseUint64 parses s as a uint64 in decimal or hexadecimal syntax.
+// Leading zeros are accepted. The empty string parses as zero.
+func ParseUint64(s string) (uint64, bool) {
+	if s == "" {
+		return 0, true
+	}
+	if len(s) >= 2 && (s[:2] == "0x" || s[:2] == "0X") {
+		v, err := strconv.ParseUint(s[2:], 16, 64)
+		return v, err == nil
+	}
+	v, err := strconv.ParseUint(s, 10, 64)
+	return v, err == nil
+}
+
+// MustParseUint64 parses s as a uint64 and panics if the string is invalid.
+func MustParseUint64(s string) uint64 {
+	v, ok := ParseUint64(s)
+	if !ok {
+		panic("invalid unsigned 64 bit integer: " + s)
+	}
+	return v
+}
+
+// BigFlag is a command line flag that accepts 256 bit big integers in decimal
+// or hexadecimal syntax.
+type BigFlag struct {
+	Name  string
+	Usage string
+	Value *big.Int
+
+	created bool
+}
+
+// String implements cli.Flag.
+func (f BigFlag) String() string {
+	return fmt.Sprintf("--%s value\t%s", f.Name, f.Usage)
+}
+
+// GetName implements cli.Flag.
+func (f BigFlag) GetName() string {
+	return f.Name
+}
+
+// Apply implements cli.Flag, registering the flag with the given flag set.
+func (f BigFlag) Apply(set *flag.FlagSet) {
+	if f.Value == nil {
+		f.Value = new(big.Int)
+	}
+	set.Var((*bigValue)(f.Value), f.Name, f.Usage)
+}
+
+// bigValue turns *big.Int into a flag.Value.
+type bigValue big.Int
+
+func (b *bigValue) String() string {
+	if b == nil {
+		return ""
+	}
+	return (*big.Int)(b).String()
+}
+
+func (b *bigValue) Set(s string) error {
+	int, ok := ParseBig256(s)
+	if !ok {
+		return fmt.Errorf("invalid integer syntax: %q", s)
+	}
+	*b = (bigValue)(*int)
+	return nil
+}
+
+// Get implements flag.Getter, returning the parsed value as a *big.Int.
+func (b *bigValue) Get() interface{} {
+	return (*big.Int)(b)
+}
+
+// Uint64Flag is a command line flag that accepts uint64 values in decimal or
+// hexadecimal syntax.
+type Uint64Flag struct {
+	Name  string
+	Usage string
+	Value uint64
+}
+
+// String implements cli.Flag.
+func (f Uint64Flag) String() string {
+	return fmt.Sprintf("--%s value\t%s (default: %d)", f.Name, f.Usage, f.Value)
+}
+
+// GetName implements cli.Flag.
+func (f Uint64Flag) GetName() string {
+	return f.Name
+}
+
+// Apply implements cli.Flag, registering the flag with the given flag set.
+func (f Uint64Flag) Apply(set *flag.FlagSet) {
+	set.Var((*uint64Value)(&f.Value), f.Name, f.Usage)
+}
+
+// uint64Value turns uint64 into a flag.Value.
+type uint64Value uint64
+
+func (i *uint64Value) String() string {
+	if i == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*i), 10)
+}
+
+func (i *uint64Value) Set(s string) error {
+	v, ok := ParseUint64(s)
+	if !ok {
+		return fmt.Errorf("invalid unsigned integer syntax: %q", s)
+	}
+	*i = uint64Value(v)
+	return nil
+}
+
+// Get implements flag.Getter, returning the parsed value as a uint64.
+func (i *uint64Value) Get() interface{} {
+	return uint64(*i)
+}
+
+// GetBig retrieves the parsed value of a BigFlag previously registered with
+// Apply, so that callers (e.g. cmd/ gas-limit or chain-id flags) can consume
+// the value after Parse without reaching into the flag.FlagSet themselves.
+func GetBig(set *flag.FlagSet, name string) *big.Int {
+	return set.Lookup(name).Value.(flag.Getter).Get().(*big.Int)
+}
+
+// GetUint64 retrieves the parsed value of a Uint64Flag previously registered
+// with Apply, so that callers (e.g. cmd/ gas-limit or price flags) can
+// consume the value after Parse without reaching into the flag.FlagSet
+// themselves.
+func GetUint64(set *flag.FlagSet, name string) uint64 {
+	return set.Lookup(name).Value.(flag.Getter).Get().(uint64)
+}
+
+var (
+	_ cli.Flag    = BigFlag{}
+	_ cli.Flag    = Uint64Flag{}
+	_ flag.Getter = (*bigValue)(nil)
+	_ flag.Getter = (*uint64Value)(nil)
+)
@@ -0,0 +1,144 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package math
+
+import (
+	"flag"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestSafeAdd(t *testing.T) {
+	tests := []struct {
+		a, b     uint64
+		want     uint64
+		overflow bool
+	}{
+		{1, 2, 3, false},
+		{math.MaxUint64, 1, 0, true},
+		{math.MaxUint64, 0, math.MaxUint64, false},
+	}
+	for _, test := range tests {
+		got, overflow := SafeAdd(test.a, test.b)
+		if overflow != test.overflow {
+			t.Errorf("SafeAdd(%d, %d) overflow = %t, want %t", test.a, test.b, overflow, test.overflow)
+		}
+		if !overflow && got != test.want {
+			t.Errorf("SafeAdd(%d, %d) = %d, want %d", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestSafeSub(t *testing.T) {
+	tests := []struct {
+		a, b      uint64
+		want      uint64
+		underflow bool
+	}{
+		{5, 2, 3, false},
+		{0, 1, math.MaxUint64, true},
+	}
+	for _, test := range tests {
+		got, underflow := SafeSub(test.a, test.b)
+		if underflow != test.underflow {
+			t.Errorf("SafeSub(%d, %d) underflow = %t, want %t", test.a, test.b, underflow, test.underflow)
+		}
+		if got != test.want {
+			t.Errorf("SafeSub(%d, %d) = %d, want %d", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestSafeMul(t *testing.T) {
+	if got, overflow := SafeMul(3, 4); overflow || got != 12 {
+		t.Errorf("SafeMul(3, 4) = (%d, %t), want (12, false)", got, overflow)
+	}
+	if _, overflow := SafeMul(math.MaxUint64, 2); !overflow {
+		t.Errorf("SafeMul(MaxUint64, 2) overflow = false, want true")
+	}
+}
+
+func TestParseUint64(t *testing.T) {
+	tests := []struct {
+		input string
+		want  uint64
+		ok    bool
+	}{
+		{"", 0, true},
+		{"0", 0, true},
+		{"0x0", 0, true},
+		{"1234", 1234, true},
+		{"0x4d2", 1234, true},
+		{"18446744073709551615", 18446744073709551615, true},
+		{"18446744073709551616", 0, false},
+		{"abcdef", 0, false},
+	}
+	for _, test := range tests {
+		got, ok := ParseUint64(test.input)
+		if ok != test.ok {
+			t.Errorf("ParseUint64(%q) -> ok = %t, want %t", test.input, ok, test.ok)
+			continue
+		}
+		if ok && got != test.want {
+			t.Errorf("ParseUint64(%q) = %d, want %d", test.input, got, test.want)
+		}
+	}
+}
+
+func TestMustParseUint64Panics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("MustParseUint64 should've panicked")
+		}
+	}()
+	MustParseUint64("ggg")
+}
+
+func TestUint64FlagRoundTrip(t *testing.T) {
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	f := Uint64Flag{Name: "gaslimit", Usage: "gas limit"}
+	f.Apply(set)
+
+	if err := set.Parse([]string{"-gaslimit", "0x4d2"}); err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	got := set.Lookup("gaslimit").Value.(interface{ String() string }).String()
+	if got != "1234" {
+		t.Errorf("gaslimit = %q, want %q", got, "1234")
+	}
+	if got := GetUint64(set, "gaslimit"); got != 1234 {
+		t.Errorf("GetUint64(gaslimit) = %d, want %d", got, 1234)
+	}
+}
+
+func TestBigFlagRoundTrip(t *testing.T) {
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	f := BigFlag{Name: "chainid", Usage: "chain id"}
+	f.Apply(set)
+
+	if err := set.Parse([]string{"-chainid", "56"}); err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	got := set.Lookup("chainid").Value.(interface{ String() string }).String()
+	if got != "56" {
+		t.Errorf("chainid = %q, want %q", got, "56")
+	}
+	if got := GetBig(set, "chainid"); got.Cmp(big.NewInt(56)) != 0 {
+		t.Errorf("GetBig(chainid) = %s, want %d", got, 56)
+	}
+}
@@ -0,0 +1,535 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package math
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// Uint256 is a fixed-width, 256 bit unsigned integer backed by four 64 bit
+// limbs in little-endian order (Uint256[0] holds the least significant word).
+// All arithmetic on Uint256 wraps modulo 2**256, is allocation-free and does
+// not mutate its operands, mirroring the semantics of U256/S256 on *big.Int
+// but without the allocation and indirection that big.Int carries.
+type Uint256 [4]uint64
+
+// Uint256FromBig returns a new Uint256 set to the value of x, truncated to
+// 256 bits. It does not modify x.
+func Uint256FromBig(x *big.Int) Uint256 {
+	var z Uint256
+	z.SetFromBig(x)
+	return z
+}
+
+// SetFromBig interprets x as a two's complement 256 bit number (after
+// reduction modulo 2**256 for values that are negative or larger than 256
+// bits) and sets z accordingly. It returns z for chaining.
+func (z *Uint256) SetFromBig(x *big.Int) *Uint256 {
+	abs := U256(new(big.Int).Set(x))
+	words := abs.Bits()
+	for i := range z {
+		if i < len(words) {
+			z[i] = uint64(words[i])
+		} else {
+			z[i] = 0
+		}
+	}
+	return z
+}
+
+// ToBig returns z as a *big.Int.
+func (z *Uint256) ToBig() *big.Int {
+	b := new(big.Int)
+	bits := make([]big.Word, 4)
+	for i, limb := range z {
+		bits[i] = big.Word(limb)
+	}
+	return b.SetBits(bits)
+}
+
+// SetBytes interprets buf as the big-endian bytes of an unsigned integer,
+// truncating to the low 256 bits, and sets z to that value.
+func (z *Uint256) SetBytes(buf []byte) *Uint256 {
+	var padded [32]byte
+	if len(buf) > 32 {
+		buf = buf[len(buf)-32:]
+	}
+	copy(padded[32-len(buf):], buf)
+	for i := 0; i < 4; i++ {
+		limb := uint64(0)
+		for j := 0; j < 8; j++ {
+			limb = limb<<8 | uint64(padded[24-i*8+j])
+		}
+		z[i] = limb
+	}
+	return z
+}
+
+// Bytes32 returns the big-endian, 32 byte representation of z.
+func (z *Uint256) Bytes32() [32]byte {
+	var out [32]byte
+	for i, limb := range z {
+		for j := 0; j < 8; j++ {
+			out[31-i*8-j] = byte(limb >> (8 * uint(j)))
+		}
+	}
+	return out
+}
+
+// IsZero reports whether z == 0.
+func (z *Uint256) IsZero() bool {
+	return z[0] == 0 && z[1] == 0 && z[2] == 0 && z[3] == 0
+}
+
+// Sign returns 0 if z == 0, otherwise 1 (Uint256 is always non-negative).
+func (z *Uint256) Sign() int {
+	if z.IsZero() {
+		return 0
+	}
+	return 1
+}
+
+// BitLen returns the minimum number of bits required to represent z.
+func (z *Uint256) BitLen() int {
+	for i := 3; i >= 0; i-- {
+		if z[i] != 0 {
+			return i*64 + bits.Len64(z[i])
+		}
+	}
+	return 0
+}
+
+// Cmp compares z and x and returns -1, 0, or 1 depending on whether z < x,
+// z == x, or z > x.
+func (z *Uint256) Cmp(x *Uint256) int {
+	for i := 3; i >= 0; i-- {
+		if z[i] != x[i] {
+			if z[i] < x[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// Add sets z to x + y mod 2**256 and returns z.
+func (z *Uint256) Add(x, y *Uint256) *Uint256 {
+	var carry uint64
+	z[0], carry = bits.Add64(x[0], y[0], 0)
+	z[1], carry = bits.Add64(x[1], y[1], carry)
+	z[2], carry = bits.Add64(x[2], y[2], carry)
+	z[3], _ = bits.Add64(x[3], y[3], carry)
+	return z
+}
+
+// Sub sets z to x - y mod 2**256 and returns z.
+func (z *Uint256) Sub(x, y *Uint256) *Uint256 {
+	var borrow uint64
+	z[0], borrow = bits.Sub64(x[0], y[0], 0)
+	z[1], borrow = bits.Sub64(x[1], y[1], borrow)
+	z[2], borrow = bits.Sub64(x[2], y[2], borrow)
+	z[3], _ = bits.Sub64(x[3], y[3], borrow)
+	return z
+}
+
+// Neg sets z to -x mod 2**256 (the two's complement negation) and returns z.
+func (z *Uint256) Neg(x *Uint256) *Uint256 {
+	var zero Uint256
+	return z.Sub(&zero, x)
+}
+
+// Mul sets z to the low 256 bits of x * y (schoolbook 4x4 limb multiply with
+// carry propagation) and returns z.
+func (z *Uint256) Mul(x, y *Uint256) *Uint256 {
+	var out [4]uint64
+	for i := 0; i < 4; i++ {
+		if x[i] == 0 {
+			continue
+		}
+		var carry uint64
+		for j := 0; j+i < 4; j++ {
+			hi, lo := bits.Mul64(x[i], y[j])
+			lo, c := bits.Add64(lo, out[i+j], 0)
+			hi += c
+			lo, c = bits.Add64(lo, carry, 0)
+			hi += c
+			out[i+j] = lo
+			carry = hi
+		}
+	}
+	*z = out
+	return z
+}
+
+// bit returns bit i (0 = LSB) of z.
+func (z *Uint256) bit(i int) uint64 {
+	return (z[i/64] >> uint(i%64)) & 1
+}
+
+// setBit sets bit i (0 = LSB) of z to 1.
+func (z *Uint256) setBit(i int) {
+	z[i/64] |= 1 << uint(i%64)
+}
+
+// reduceStep folds one more bit, inBit, into rem as part of a binary long
+// division by mod, and reports whether mod was subtracted out (the quotient
+// bit for that position). The caller must maintain the invariant rem < mod
+// between calls.
+//
+// A plain "shift rem left, OR in inBit, subtract mod if >= mod" doesn't fit
+// in a fixed-width Uint256 when mod exceeds 2**255: rem < mod can be up to
+// 2**256-1, so 2*rem+inBit can need 257 bits, and Lsh silently drops the top
+// bit. reduceStep tracks that bit (the carry out of rem's top limb) itself:
+// if it's set, the true (unbounded) shifted value is >= 2**256 > mod, so mod
+// must be subtracted unconditionally — and the 256-bit subtraction computes
+// the right answer regardless, because it implicitly cancels the same
+// 2**256 term that the shift dropped.
+func reduceStep(rem *Uint256, inBit uint64, mod *Uint256) (subtracted bool) {
+	carry := rem[3] >> 63
+	rem.Lsh(rem, 1)
+	rem[0] |= inBit
+	if carry != 0 || rem.Cmp(mod) >= 0 {
+		rem.Sub(rem, mod)
+		return true
+	}
+	return false
+}
+
+// quoRem divides x by y using binary long division (repeated shift-and-
+// subtract over the bits of x), returning the quotient and remainder. This is
+// a bit-at-a-time algorithm rather than Knuth's word-at-a-time Algorithm D,
+// so unlike Add/Mul/Exp it is not expected to outrun big.Int's native
+// division (see BenchmarkUint256Div/BenchmarkBigIntDiv) — it exists to keep
+// DIV/MOD alloc-free and dependency-free, not to be the fast path. Every
+// intermediate value is a stack-allocated Uint256, so this never touches the
+// heap. Division by zero returns (0, 0), matching EVM DIV/MOD semantics.
+func quoRem(x, y *Uint256) (q, r Uint256) {
+	if y.IsZero() {
+		return Uint256{}, Uint256{}
+	}
+	var rem, quot Uint256
+	for i := x.BitLen() - 1; i >= 0; i-- {
+		if reduceStep(&rem, x.bit(i), y) {
+			quot.setBit(i)
+		}
+	}
+	return quot, rem
+}
+
+// modWide reduces the little-endian limb slice num (which may be wider than
+// 256 bits, e.g. the output of an unreduced add or multiply) modulo mod using
+// the same bit-at-a-time binary long division as quoRem, and returns only the
+// remainder. Like quoRem, this trades the throughput of a Barrett/Montgomery
+// reduction for a simple, alloc-free implementation (see
+// BenchmarkUint256AddMod/BenchmarkUint256MulMod against their big.Int
+// counterparts). mod must be non-zero; callers check that before calling
+// modWide.
+func modWide(num []uint64, mod *Uint256) Uint256 {
+	var rem Uint256
+	for i := wideBitLen(num) - 1; i >= 0; i-- {
+		reduceStep(&rem, wideBit(num, i), mod)
+	}
+	return rem
+}
+
+// wideBit returns bit i (0 = LSB) of the little-endian limb slice num.
+func wideBit(num []uint64, i int) uint64 {
+	limb := i / 64
+	if limb >= len(num) {
+		return 0
+	}
+	return (num[limb] >> uint(i%64)) & 1
+}
+
+// wideBitLen returns the minimum number of bits required to represent the
+// little-endian limb slice num.
+func wideBitLen(num []uint64) int {
+	for i := len(num) - 1; i >= 0; i-- {
+		if num[i] != 0 {
+			return i*64 + bits.Len64(num[i])
+		}
+	}
+	return 0
+}
+
+// Div sets z to x / y (unsigned, truncated towards zero). Division by zero
+// sets z to zero, matching EVM DIV semantics.
+func (z *Uint256) Div(x, y *Uint256) *Uint256 {
+	q, _ := quoRem(x, y)
+	*z = q
+	return z
+}
+
+// Mod sets z to x % y (unsigned). Division by zero sets z to zero, matching
+// EVM MOD semantics.
+func (z *Uint256) Mod(x, y *Uint256) *Uint256 {
+	_, r := quoRem(x, y)
+	*z = r
+	return z
+}
+
+// AddMod sets z to (x + y) % m and returns z. Division by zero sets z to
+// zero. x + y is computed into a 5-limb intermediate before reducing, since
+// the unbounded sum can carry one bit past 256 — reducing the wrapped
+// (mod 2**256) sum first would silently drop that carry and give the wrong
+// answer for the ADDMOD opcode.
+func (z *Uint256) AddMod(x, y, m *Uint256) *Uint256 {
+	if m.IsZero() {
+		*z = Uint256{}
+		return z
+	}
+	var sum [5]uint64
+	var carry uint64
+	sum[0], carry = bits.Add64(x[0], y[0], 0)
+	sum[1], carry = bits.Add64(x[1], y[1], carry)
+	sum[2], carry = bits.Add64(x[2], y[2], carry)
+	sum[3], carry = bits.Add64(x[3], y[3], carry)
+	sum[4] = carry
+	*z = modWide(sum[:], m)
+	return z
+}
+
+// mulFull returns the full, untruncated 512 bit product of x and y as eight
+// little-endian limbs (schoolbook 4x4 multiply with full carry propagation,
+// unlike Mul which discards everything above the low 256 bits).
+func mulFull(x, y *Uint256) [8]uint64 {
+	var out [8]uint64
+	for i := 0; i < 4; i++ {
+		if x[i] == 0 {
+			continue
+		}
+		var carry uint64
+		for j := 0; j < 4; j++ {
+			hi, lo := bits.Mul64(x[i], y[j])
+			lo, c := bits.Add64(lo, out[i+j], 0)
+			hi += c
+			lo, c = bits.Add64(lo, carry, 0)
+			hi += c
+			out[i+j] = lo
+			carry = hi
+		}
+		for k := i + 4; carry != 0; k++ {
+			out[k], carry = bits.Add64(out[k], carry, 0)
+		}
+	}
+	return out
+}
+
+// MulMod sets z to (x * y) % m and returns z. Division by zero sets z to
+// zero. x * y is computed into a full 512 bit intermediate before reducing,
+// since the unbounded product can be twice as wide as either operand.
+func (z *Uint256) MulMod(x, y, m *Uint256) *Uint256 {
+	if m.IsZero() {
+		*z = Uint256{}
+		return z
+	}
+	product := mulFull(x, y)
+	*z = modWide(product[:], m)
+	return z
+}
+
+// Exp sets z to x**y mod 2**256 using square-and-multiply and returns z.
+func (z *Uint256) Exp(x, y *Uint256) *Uint256 {
+	result := Uint256{1, 0, 0, 0}
+	base := *x
+	for i := 0; i < 4; i++ {
+		word := y[i]
+		for b := 0; b < 64; b++ {
+			if word&1 == 1 {
+				result.Mul(&result, &base)
+			}
+			base.Mul(&base, &base)
+			word >>= 1
+		}
+	}
+	*z = result
+	return z
+}
+
+// Lsh sets z to x << n and returns z.
+func (z *Uint256) Lsh(x *Uint256, n uint) *Uint256 {
+	if n >= 256 {
+		*z = Uint256{}
+		return z
+	}
+	limbShift, bitShift := n/64, n%64
+	var out Uint256
+	for i := 3; i >= 0; i-- {
+		if uint(i) < limbShift {
+			continue
+		}
+		src := uint(i) - limbShift
+		out[i] = x[src] << bitShift
+		if bitShift != 0 && src > 0 {
+			out[i] |= x[src-1] >> (64 - bitShift)
+		}
+	}
+	*z = out
+	return z
+}
+
+// Rsh sets z to x >> n and returns z.
+func (z *Uint256) Rsh(x *Uint256, n uint) *Uint256 {
+	if n >= 256 {
+		*z = Uint256{}
+		return z
+	}
+	limbShift, bitShift := n/64, n%64
+	var out Uint256
+	for i := 0; i < 4; i++ {
+		src := uint(i) + limbShift
+		if src >= 4 {
+			continue
+		}
+		out[i] = x[src] >> bitShift
+		if bitShift != 0 && src+1 < 4 {
+			out[i] |= x[src+1] << (64 - bitShift)
+		}
+	}
+	*z = out
+	return z
+}
+
+// And sets z to x & y and returns z.
+func (z *Uint256) And(x, y *Uint256) *Uint256 {
+	for i := range z {
+		z[i] = x[i] & y[i]
+	}
+	return z
+}
+
+// Or sets z to x | y and returns z.
+func (z *Uint256) Or(x, y *Uint256) *Uint256 {
+	for i := range z {
+		z[i] = x[i] | y[i]
+	}
+	return z
+}
+
+// Xor sets z to x ^ y and returns z.
+func (z *Uint256) Xor(x, y *Uint256) *Uint256 {
+	for i := range z {
+		z[i] = x[i] ^ y[i]
+	}
+	return z
+}
+
+// Not sets z to ^x (bitwise complement) and returns z.
+func (z *Uint256) Not(x *Uint256) *Uint256 {
+	for i := range z {
+		z[i] = ^x[i]
+	}
+	return z
+}
+
+// SignExtend sets z to the value obtained by treating the byte at index
+// byteNum (0 = least significant byte) of x as the sign byte and extending
+// it through the remaining, more significant bytes. It mirrors the EVM
+// SIGNEXTEND opcode and returns z.
+func (z *Uint256) SignExtend(x *Uint256, byteNum uint) *Uint256 {
+	if byteNum >= 32 {
+		*z = *x
+		return z
+	}
+	bit := byteNum*8 + 7
+	limb, off := bit/64, bit%64
+	signed := x[limb]>>off&1 != 0
+	*z = *x
+	// Clear all bits above bit, then set them according to the sign.
+	if off+1 < 64 {
+		mask := ^uint64(0) << (off + 1)
+		if signed {
+			z[limb] |= mask
+		} else {
+			z[limb] &^= mask
+		}
+	}
+	for i := limb + 1; i < 4; i++ {
+		if signed {
+			z[i] = ^uint64(0)
+		} else {
+			z[i] = 0
+		}
+	}
+	return z
+}
+
+// Slt interprets x and y as 256 bit two's complement signed integers and
+// reports whether x < y, matching the EVM SLT opcode.
+func Slt(x, y *Uint256) bool {
+	xNeg, yNeg := x[3]>>63 != 0, y[3]>>63 != 0
+	if xNeg != yNeg {
+		return xNeg
+	}
+	return x.Cmp(y) < 0
+}
+
+// Sgt interprets x and y as 256 bit two's complement signed integers and
+// reports whether x > y, matching the EVM SGT opcode.
+func Sgt(x, y *Uint256) bool {
+	return Slt(y, x)
+}
+
+// absUint256 returns the two's complement absolute value of x (interpreted
+// as a signed 256 bit integer) and whether x was negative. Note that, as
+// with any two's complement signed type, the minimum value has no positive
+// counterpart: Neg of it returns itself, which is what gives SDiv/SMod their
+// EVM-matching MinInt256 / -1 == MinInt256 behaviour below.
+func absUint256(x *Uint256) (abs Uint256, neg bool) {
+	if x[3]>>63 != 0 {
+		abs.Neg(x)
+		return abs, true
+	}
+	return *x, false
+}
+
+// SDiv sets z to x / y using signed (two's complement) division, truncated
+// towards zero, and returns z. Division by zero sets z to zero.
+func (z *Uint256) SDiv(x, y *Uint256) *Uint256 {
+	if y.IsZero() {
+		*z = Uint256{}
+		return z
+	}
+	ax, xNeg := absUint256(x)
+	ay, yNeg := absUint256(y)
+	q, _ := quoRem(&ax, &ay)
+	if xNeg != yNeg {
+		q.Neg(&q)
+	}
+	*z = q
+	return z
+}
+
+// SMod sets z to x % y using signed (two's complement) division and returns
+// z. The remainder takes the sign of the dividend, matching the EVM SMOD
+// opcode. Division by zero sets z to zero.
+func (z *Uint256) SMod(x, y *Uint256) *Uint256 {
+	if y.IsZero() {
+		*z = Uint256{}
+		return z
+	}
+	ax, xNeg := absUint256(x)
+	ay, _ := absUint256(y)
+	_, r := quoRem(&ax, &ay)
+	if xNeg {
+		r.Neg(&r)
+	}
+	*z = r
+	return z
+}
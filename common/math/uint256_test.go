@@ -0,0 +1,447 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package math
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestUint256ArithmeticMatchesBigInt(t *testing.T) {
+	tests := []struct {
+		x, y string
+	}{
+		{"0", "0"},
+		{"1", "1"},
+		{"123456789123456789123456789", "987654321"},
+		{"115792089237316195423570985008687907853269984665640564039457584007913129639935", "1"},
+		{"0", "1"},
+	}
+	for _, test := range tests {
+		x := Uint256FromBig(MustParseBig256OrDecimal(test.x))
+		y := Uint256FromBig(MustParseBig256OrDecimal(test.y))
+
+		var sum Uint256
+		sum.Add(&x, &y)
+		wantSum := U256(new(big.Int).Add(x.ToBig(), y.ToBig()))
+		if sum.ToBig().Cmp(wantSum) != 0 {
+			t.Errorf("Add(%s, %s) = %s, want %s", test.x, test.y, sum.ToBig(), wantSum)
+		}
+
+		var prod Uint256
+		prod.Mul(&x, &y)
+		wantProd := U256(new(big.Int).Mul(x.ToBig(), y.ToBig()))
+		if prod.ToBig().Cmp(wantProd) != 0 {
+			t.Errorf("Mul(%s, %s) = %s, want %s", test.x, test.y, prod.ToBig(), wantProd)
+		}
+	}
+}
+
+func TestUint256DivMod(t *testing.T) {
+	x := Uint256FromBig(big.NewInt(17))
+	y := Uint256FromBig(big.NewInt(5))
+
+	var q, r Uint256
+	q.Div(&x, &y)
+	r.Mod(&x, &y)
+	if q.ToBig().Cmp(big.NewInt(3)) != 0 {
+		t.Errorf("Div(17, 5) = %s, want 3", q.ToBig())
+	}
+	if r.ToBig().Cmp(big.NewInt(2)) != 0 {
+		t.Errorf("Mod(17, 5) = %s, want 2", r.ToBig())
+	}
+
+	var zero Uint256
+	var q2 Uint256
+	q2.Div(&x, &zero)
+	if !q2.IsZero() {
+		t.Errorf("Div(17, 0) = %s, want 0", q2.ToBig())
+	}
+}
+
+func TestUint256BitLenAndCmp(t *testing.T) {
+	a := Uint256FromBig(big.NewInt(0x100))
+	b := Uint256FromBig(big.NewInt(0xff))
+	if a.BitLen() != 9 {
+		t.Errorf("BitLen(0x100) = %d, want 9", a.BitLen())
+	}
+	if a.Cmp(&b) <= 0 {
+		t.Errorf("Cmp(0x100, 0xff) <= 0, want > 0")
+	}
+}
+
+func TestUint256BytesRoundTrip(t *testing.T) {
+	x := Uint256FromBig(MustParseBig256("0x0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f20"))
+	buf := x.Bytes32()
+	var y Uint256
+	y.SetBytes(buf[:])
+	if x.Cmp(&y) != 0 {
+		t.Errorf("round-trip through Bytes32/SetBytes changed value: %s != %s", x.ToBig(), y.ToBig())
+	}
+}
+
+func TestUint256SignExtend(t *testing.T) {
+	tests := []struct {
+		x       string
+		byteNum uint
+		want    string
+	}{
+		{"0xff", 0, "0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"},
+		{"0x7f", 0, "0x7f"},
+		{"0x1", 31, "0x1"},
+	}
+	for _, test := range tests {
+		x := Uint256FromBig(MustParseBig256(test.x))
+		var z Uint256
+		z.SignExtend(&x, test.byteNum)
+		want := U256(MustParseBig256(test.want))
+		if z.ToBig().Cmp(want) != 0 {
+			t.Errorf("SignExtend(%s, %d) = %s, want %s", test.x, test.byteNum, z.ToBig(), want)
+		}
+	}
+}
+
+// TestUint256AddModOverflow guards against regressing to computing
+// (x+y) mod 2**256 mod m (which silently drops the carry out of the top
+// limb) instead of the true unbounded (x+y) mod m that EVM ADDMOD requires.
+func TestUint256AddModOverflow(t *testing.T) {
+	x := Uint256FromBig(new(big.Int).Sub(BigPow(2, 256), big.NewInt(1))) // 2**256-1
+	y := Uint256FromBig(big.NewInt(5))
+	m := Uint256FromBig(big.NewInt(7))
+
+	var z Uint256
+	z.AddMod(&x, &y, &m)
+	if want := big.NewInt(6); z.ToBig().Cmp(want) != 0 {
+		t.Errorf("AddMod(2**256-1, 5, 7) = %s, want %s", z.ToBig(), want)
+	}
+}
+
+// refUint256Cases returns the fixed boundary values below followed by
+// pseudo-random 256 bit values drawn from r until there are at least n (all
+// boundary values are always returned, even when n is smaller), so every
+// property test below exercises carry- and borrow-at-limb-boundary cases in
+// addition to pure randoms.
+func refUint256Cases(r *rand.Rand, n int) []*big.Int {
+	cases := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		big.NewInt(-1), // == 2**256-1 after U256 wrapping
+		BigPow(2, 63),
+		BigPow(2, 64),
+		BigPow(2, 127),
+		BigPow(2, 128),
+		BigPow(2, 191),
+		BigPow(2, 192),
+		BigPow(2, 255),
+		new(big.Int).Sub(BigPow(2, 256), big.NewInt(1)),
+		new(big.Int).Sub(BigPow(2, 64), big.NewInt(1)),
+		new(big.Int).Sub(BigPow(2, 128), big.NewInt(1)),
+	}
+	for len(cases) < n {
+		buf := make([]byte, 32)
+		r.Read(buf)
+		cases = append(cases, new(big.Int).SetBytes(buf))
+	}
+	return cases
+}
+
+func TestUint256ExpMatchesBigInt(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, xb := range refUint256Cases(r, 12) {
+		for _, yb := range refUint256Cases(r, 4) {
+			x, y := Uint256FromBig(xb), Uint256FromBig(yb)
+			var z Uint256
+			z.Exp(&x, &y)
+			want := new(big.Int).Exp(x.ToBig(), y.ToBig(), tt256)
+			if z.ToBig().Cmp(want) != 0 {
+				t.Fatalf("Exp(%s, %s) = %s, want %s", x.ToBig(), y.ToBig(), z.ToBig(), want)
+			}
+		}
+	}
+}
+
+func TestUint256LshRshMatchBigInt(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	shifts := []uint{0, 1, 7, 8, 63, 64, 65, 127, 128, 191, 192, 255, 256, 300}
+	for _, xb := range refUint256Cases(r, 10) {
+		x := Uint256FromBig(xb)
+		for _, n := range shifts {
+			var lsh, rsh Uint256
+			lsh.Lsh(&x, n)
+			rsh.Rsh(&x, n)
+
+			wantLsh := U256(new(big.Int).Lsh(x.ToBig(), n))
+			wantRsh := new(big.Int).Rsh(x.ToBig(), n)
+			if lsh.ToBig().Cmp(wantLsh) != 0 {
+				t.Errorf("Lsh(%s, %d) = %s, want %s", x.ToBig(), n, lsh.ToBig(), wantLsh)
+			}
+			if rsh.ToBig().Cmp(wantRsh) != 0 {
+				t.Errorf("Rsh(%s, %d) = %s, want %s", x.ToBig(), n, rsh.ToBig(), wantRsh)
+			}
+		}
+	}
+}
+
+func TestUint256BitwiseMatchBigInt(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	cases := refUint256Cases(r, 12)
+	for _, xb := range cases {
+		x := Uint256FromBig(xb)
+
+		var not Uint256
+		not.Not(&x)
+		if want := new(big.Int).Xor(x.ToBig(), MaxBig256); not.ToBig().Cmp(want) != 0 {
+			t.Errorf("Not(%s) = %s, want %s", x.ToBig(), not.ToBig(), want)
+		}
+
+		var neg Uint256
+		neg.Neg(&x)
+		if want := U256(new(big.Int).Neg(x.ToBig())); neg.ToBig().Cmp(want) != 0 {
+			t.Errorf("Neg(%s) = %s, want %s", x.ToBig(), neg.ToBig(), want)
+		}
+
+		for _, yb := range cases {
+			y := Uint256FromBig(yb)
+
+			var and, or, xor Uint256
+			and.And(&x, &y)
+			or.Or(&x, &y)
+			xor.Xor(&x, &y)
+
+			if want := new(big.Int).And(x.ToBig(), y.ToBig()); and.ToBig().Cmp(want) != 0 {
+				t.Errorf("And(%s, %s) = %s, want %s", x.ToBig(), y.ToBig(), and.ToBig(), want)
+			}
+			if want := new(big.Int).Or(x.ToBig(), y.ToBig()); or.ToBig().Cmp(want) != 0 {
+				t.Errorf("Or(%s, %s) = %s, want %s", x.ToBig(), y.ToBig(), or.ToBig(), want)
+			}
+			if want := new(big.Int).Xor(x.ToBig(), y.ToBig()); xor.ToBig().Cmp(want) != 0 {
+				t.Errorf("Xor(%s, %s) = %s, want %s", x.ToBig(), y.ToBig(), xor.ToBig(), want)
+			}
+		}
+	}
+}
+
+func TestUint256AddModMulModMatchBigInt(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	cases := refUint256Cases(r, 10)
+	mods := append([]*big.Int{big.NewInt(0)}, refUint256Cases(r, 6)...)
+	for _, xb := range cases {
+		x := Uint256FromBig(xb)
+		for _, yb := range cases {
+			y := Uint256FromBig(yb)
+			for _, mb := range mods {
+				m := Uint256FromBig(mb)
+
+				var addMod, mulMod Uint256
+				addMod.AddMod(&x, &y, &m)
+				mulMod.MulMod(&x, &y, &m)
+
+				var wantAdd, wantMul *big.Int
+				if mb.Sign() == 0 {
+					wantAdd, wantMul = big.NewInt(0), big.NewInt(0)
+				} else {
+					// m.ToBig() is the U256-wrapped modulus actually used by
+					// AddMod/MulMod above; mb itself may still be negative
+					// (e.g. -1), and big.Int.Mod with a negative modulus
+					// always returns 0, which isn't what we want here.
+					mw := m.ToBig()
+					wantAdd = new(big.Int).Mod(new(big.Int).Add(x.ToBig(), y.ToBig()), mw)
+					wantMul = new(big.Int).Mod(new(big.Int).Mul(x.ToBig(), y.ToBig()), mw)
+				}
+				if addMod.ToBig().Cmp(wantAdd) != 0 {
+					t.Fatalf("AddMod(%s, %s, %s) = %s, want %s", x.ToBig(), y.ToBig(), mb, addMod.ToBig(), wantAdd)
+				}
+				if mulMod.ToBig().Cmp(wantMul) != 0 {
+					t.Fatalf("MulMod(%s, %s, %s) = %s, want %s", x.ToBig(), y.ToBig(), mb, mulMod.ToBig(), wantMul)
+				}
+			}
+		}
+	}
+}
+
+func TestUint256SDivSModMatchBigInt(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	cases := append(refUint256Cases(r, 10), BigPow(2, 255)) // MinInt256
+	divisors := append([]*big.Int{big.NewInt(0), big.NewInt(-1)}, refUint256Cases(r, 6)...)
+	for _, xb := range cases {
+		x := Uint256FromBig(xb)
+		for _, yb := range divisors {
+			y := Uint256FromBig(yb)
+
+			var sdiv, smod Uint256
+			sdiv.SDiv(&x, &y)
+			smod.SMod(&x, &y)
+
+			xs, ys := S256(x.ToBig()), S256(y.ToBig())
+			var wantDiv, wantMod *big.Int
+			if ys.Sign() == 0 {
+				wantDiv, wantMod = big.NewInt(0), big.NewInt(0)
+			} else {
+				wantDiv = U256(new(big.Int).Quo(xs, ys))
+				wantMod = U256(new(big.Int).Rem(xs, ys))
+			}
+			if sdiv.ToBig().Cmp(wantDiv) != 0 {
+				t.Fatalf("SDiv(%s, %s) = %s, want %s", xs, ys, sdiv.ToBig(), wantDiv)
+			}
+			if smod.ToBig().Cmp(wantMod) != 0 {
+				t.Fatalf("SMod(%s, %s) = %s, want %s", xs, ys, smod.ToBig(), wantMod)
+			}
+		}
+	}
+}
+
+func TestUint256SltSgt(t *testing.T) {
+	negOne := Uint256FromBig(big.NewInt(-1))
+	one := Uint256FromBig(big.NewInt(1))
+	if !Slt(&negOne, &one) {
+		t.Errorf("Slt(-1, 1) = false, want true")
+	}
+	if !Sgt(&one, &negOne) {
+		t.Errorf("Sgt(1, -1) = false, want true")
+	}
+}
+
+// MustParseBig256OrDecimal parses a plain decimal string into a *big.Int,
+// used only to build test fixtures above.
+func MustParseBig256OrDecimal(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("invalid decimal: " + s)
+	}
+	return n
+}
+
+func BenchmarkUint256Add(b *testing.B) {
+	x := Uint256FromBig(MustParseBig256("123456789123456789123456789123456789"))
+	y := Uint256FromBig(MustParseBig256("987654321987654321987654321987654321"))
+	var z Uint256
+	for i := 0; i < b.N; i++ {
+		z.Add(&x, &y)
+	}
+}
+
+func BenchmarkBigIntAdd(b *testing.B) {
+	x := MustParseBig256("123456789123456789123456789123456789")
+	y := MustParseBig256("987654321987654321987654321987654321")
+	for i := 0; i < b.N; i++ {
+		U256(new(big.Int).Add(x, y))
+	}
+}
+
+func BenchmarkUint256Exp(b *testing.B) {
+	x := Uint256FromBig(big.NewInt(3))
+	y := Uint256FromBig(big.NewInt(144))
+	var z Uint256
+	for i := 0; i < b.N; i++ {
+		z.Exp(&x, &y)
+	}
+}
+
+func BenchmarkBigIntExp(b *testing.B) {
+	x := big.NewInt(3)
+	y := big.NewInt(144)
+	for i := 0; i < b.N; i++ {
+		Exp(new(big.Int).Set(x), y)
+	}
+}
+
+func BenchmarkUint256Div(b *testing.B) {
+	x := Uint256FromBig(MustParseBig256("123456789123456789123456789123456789"))
+	y := Uint256FromBig(big.NewInt(987654321))
+	var z Uint256
+	for i := 0; i < b.N; i++ {
+		z.Div(&x, &y)
+	}
+}
+
+func BenchmarkBigIntDiv(b *testing.B) {
+	x := MustParseBig256("123456789123456789123456789123456789")
+	y := big.NewInt(987654321)
+	for i := 0; i < b.N; i++ {
+		U256(new(big.Int).Div(x, y))
+	}
+}
+
+func BenchmarkUint256Mod(b *testing.B) {
+	x := Uint256FromBig(MustParseBig256("123456789123456789123456789123456789"))
+	y := Uint256FromBig(big.NewInt(987654321))
+	var z Uint256
+	for i := 0; i < b.N; i++ {
+		z.Mod(&x, &y)
+	}
+}
+
+func BenchmarkBigIntMod(b *testing.B) {
+	x := MustParseBig256("123456789123456789123456789123456789")
+	y := big.NewInt(987654321)
+	for i := 0; i < b.N; i++ {
+		U256(new(big.Int).Mod(x, y))
+	}
+}
+
+func BenchmarkUint256AddMod(b *testing.B) {
+	x := Uint256FromBig(MustParseBig256("115792089237316195423570985008687907853269984665640564039457584007913129639935"))
+	y := Uint256FromBig(MustParseBig256("115792089237316195423570985008687907853269984665640564039457584007913129639935"))
+	m := Uint256FromBig(MustParseBig256("987654321987654321987654321987654321"))
+	var z Uint256
+	for i := 0; i < b.N; i++ {
+		z.AddMod(&x, &y, &m)
+	}
+}
+
+func BenchmarkBigIntAddMod(b *testing.B) {
+	x := MustParseBig256("115792089237316195423570985008687907853269984665640564039457584007913129639935")
+	y := MustParseBig256("115792089237316195423570985008687907853269984665640564039457584007913129639935")
+	m := MustParseBig256("987654321987654321987654321987654321")
+	for i := 0; i < b.N; i++ {
+		U256(new(big.Int).Mod(new(big.Int).Add(x, y), m))
+	}
+}
+
+func BenchmarkUint256MulMod(b *testing.B) {
+	x := Uint256FromBig(MustParseBig256("115792089237316195423570985008687907853269984665640564039457584007913129639935"))
+	y := Uint256FromBig(MustParseBig256("115792089237316195423570985008687907853269984665640564039457584007913129639935"))
+	m := Uint256FromBig(MustParseBig256("987654321987654321987654321987654321"))
+	var z Uint256
+	for i := 0; i < b.N; i++ {
+		z.MulMod(&x, &y, &m)
+	}
+}
+
+func BenchmarkBigIntMulMod(b *testing.B) {
+	x := MustParseBig256("115792089237316195423570985008687907853269984665640564039457584007913129639935")
+	y := MustParseBig256("115792089237316195423570985008687907853269984665640564039457584007913129639935")
+	m := MustParseBig256("987654321987654321987654321987654321")
+	for i := 0; i < b.N; i++ {
+		U256(new(big.Int).Mod(new(big.Int).Mul(x, y), m))
+	}
+}
+
+func BenchmarkUint256PaddedBytes(b *testing.B) {
+	x := Uint256FromBig(MustParseBig256("123456789123456789123456789"))
+	for i := 0; i < b.N; i++ {
+		_ = x.Bytes32()
+	}
+}
+
+func BenchmarkBigIntPaddedBigBytes(b *testing.B) {
+	x := MustParseBig256("123456789123456789123456789")
+	for i := 0; i < b.N; i++ {
+		PaddedBigBytes(x, 32)
+	}
+}